@@ -0,0 +1,124 @@
+package courierhttp
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"testing"
+)
+
+func TestBindMultipart(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	if err := w.WriteField("name", "jojo"); err != nil {
+		t.Fatal(err)
+	}
+
+	fw, err := w.CreateFormFile("avatar", "avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantContent := bytes.Repeat([]byte("x"), 1<<16)
+	if _, err := fw.Write(wantContent); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type UploadAvatar struct {
+		Name   string     `name:"name"`
+		Avatar FileHeader `name:"avatar"`
+	}
+
+	dst := &UploadAvatar{}
+
+	r := multipart.NewReader(buf, w.Boundary())
+	if err := BindMultipart(r, dst); err != nil {
+		t.Fatalf("BindMultipart failed: %v", err)
+	}
+
+	if dst.Name != "jojo" {
+		t.Fatalf("expected name %q to be bound before the file part, got %q", "jojo", dst.Name)
+	}
+
+	if dst.Avatar == nil {
+		t.Fatal("expected Avatar to be bound")
+	}
+
+	got, err := io.ReadAll(dst.Avatar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, wantContent) {
+		t.Fatalf("expected the file part to still hold its %d bytes, got %d bytes", len(wantContent), len(got))
+	}
+}
+
+func TestBindMultipartDropsReadOnlyField(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	if err := w.WriteField("id", "server-assigned-should-be-ignored"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteField("name", "jojo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type CreateUser struct {
+		ID   string `name:"id" validate:"readOnly"`
+		Name string `name:"name"`
+	}
+
+	dst := &CreateUser{}
+
+	r := multipart.NewReader(buf, w.Boundary())
+	if err := BindMultipart(r, dst); err != nil {
+		t.Fatalf("BindMultipart failed: %v", err)
+	}
+
+	if dst.ID != "" {
+		t.Fatalf("expected readOnly field ID to be dropped, got %q", dst.ID)
+	}
+	if dst.Name != "jojo" {
+		t.Fatalf("expected Name to still be bound, got %q", dst.Name)
+	}
+}
+
+func TestBindMultipartErrorsWhenFieldFollowsFilePart(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	fw, err := w.CreateFormFile("avatar", "avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteField("name", "jojo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type UploadAvatar struct {
+		Avatar FileHeader `name:"avatar"`
+		Name   string     `name:"name"`
+	}
+
+	dst := &UploadAvatar{}
+
+	r := multipart.NewReader(buf, w.Boundary())
+	if err := BindMultipart(r, dst); err == nil {
+		t.Fatal("expected an error because Name never receives a part once the file field is bound")
+	}
+}
@@ -23,11 +23,9 @@ import (
 	"github.com/pkg/errors"
 )
 
-type OpenAPIBuildFunc func(r courier.Router, fns ...BuildOptionFunc) *openapi.OpenAPI
+type OpenAPIBuildFunc func(r courier.Router, fns ...BuildOptionFunc) (*openapi.OpenAPI, error)
 
-var DefaultOpenAPIBuildFunc = func(r courier.Router, fns ...BuildOptionFunc) *openapi.OpenAPI {
-	return FromRouter(r, fns...)
-}
+var DefaultOpenAPIBuildFunc OpenAPIBuildFunc = FromRouter
 
 type CanResponseStatusCode interface {
 	ResponseStatusCode() int
@@ -45,19 +43,39 @@ type CanResponseErrors interface {
 	ResponseErrors() []error
 }
 
+// CanResponseVariants is implemented by operators whose result may be one
+// of several typed courierhttp.ResponseVariant shapes, each registered as
+// its own entry under op.Responses.
+type CanResponseVariants interface {
+	ResponseVariants() []courierhttp.ResponseVariant
+}
+
 func Naming(naming func(t string) string) BuildOptionFunc {
 	return func(o *buildOption) {
 		o.naming = naming
 	}
 }
 
+// WithSchemaPostProcessor runs postProcess on every schema right after it
+// is registered under Components.Schemas, keyed by its emitted name.
+func WithSchemaPostProcessor(postProcess func(name string, s *jsonschema.Schema)) BuildOptionFunc {
+	return func(o *buildOption) {
+		o.schemaPostProcessor = postProcess
+	}
+}
+
 type BuildOptionFunc func(o *buildOption)
 
 type buildOption struct {
-	naming func(t string) string
+	naming              func(t string) string
+	schemaPostProcessor func(name string, s *jsonschema.Schema)
 }
 
-func FromRouter(r courier.Router, fns ...BuildOptionFunc) *openapi.OpenAPI {
+// FromRouter scans every route of r into an OpenAPI document. If scanning
+// finds any problem -- a missing `in` tag, an un-transformable field type,
+// a duplicate schema name -- it returns all of them at once as a
+// ScanErrors, instead of stopping at the first one.
+func FromRouter(r courier.Router, fns ...BuildOptionFunc) (*openapi.OpenAPI, error) {
 	b := &scanner{
 		o:   openapi.NewOpenAPI(),
 		opt: buildOption{},
@@ -97,11 +115,40 @@ func FromRouter(r courier.Router, fns ...BuildOptionFunc) *openapi.OpenAPI {
 
 	for i := range routes {
 		if err := b.scan(routes[i]); err != nil {
-			panic(err)
+			b.errs = append(b.errs, err)
 		}
 	}
 
-	return b.o
+	if len(b.errs) > 0 {
+		return nil, b.errs
+	}
+
+	return b.o, nil
+}
+
+// MustFromRouter is FromRouter for callers that can't do anything but
+// panic on a build-time report -- e.g. building the document at package
+// init. Prefer FromRouter wherever the caller can surface the error.
+func MustFromRouter(r courier.Router, fns ...BuildOptionFunc) *openapi.OpenAPI {
+	doc, err := FromRouter(r, fns...)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+// ScanErrors aggregates every problem found while scanning routes into
+// openapi operations -- missing `in` tags, un-transformable field types,
+// duplicate schema names -- so a single build reports everything it found
+// at once instead of panicking on the first one.
+type ScanErrors []error
+
+func (e ScanErrors) Error() string {
+	messages := make([]string, len(e))
+	for i := range e {
+		messages[i] = e[i].Error()
+	}
+	return fmt.Sprintf("%d scan error(s):\n%s", len(e), strings.Join(messages, "\n"))
 }
 
 type scanner struct {
@@ -109,6 +156,7 @@ type scanner struct {
 	m                 sync.Map
 	incomingTransport transport.IncomingTransport
 	opt               buildOption
+	errs              ScanErrors
 }
 
 func (b *scanner) Record(typeRef string) bool {
@@ -190,8 +238,12 @@ func (b *scanner) RegisterSchema(ref string, s *jsonschema.Schema) {
 
 	if _, ok := b.o.Components.Schemas[n]; !ok {
 		b.o.Components.Schemas[n] = s
+
+		if b.opt.schemaPostProcessor != nil {
+			b.opt.schemaPostProcessor(n, s)
+		}
 	} else {
-		fmt.Println(n, "Registered.")
+		b.errs = append(b.errs, errors.Errorf("duplicate schema name %s", n))
 	}
 }
 
@@ -220,6 +272,17 @@ func (b *scanner) scanResponse(ctx context.Context, op *openapi.Operation, o *co
 		return
 	}
 
+	if can, ok := o.Operator.(CanResponseVariants); ok {
+		for _, variant := range can.ResponseVariants() {
+			variantResp := &openapi.Response{}
+			mt := &openapi.MediaType{}
+			mt.Schema = b.SchemaFromType(ctx, variant.ContentSchema(), false)
+			variantResp.AddContent(variant.ContentType(), mt)
+			op.AddResponse(variant.StatusCode(), variantResp)
+		}
+		return
+	}
+
 	if can, ok := o.Operator.(CanResponseStatusCode); ok {
 		statusCode = can.ResponseStatusCode()
 	}
@@ -282,20 +345,40 @@ func (b *scanner) scanParameterOrRequestBody(ctx context.Context, op *openapi.Op
 		location, _ := tagValueAndFlagsByTagString(field.Tag().Get("in"))
 
 		if location == "" {
-			panic(errors.Errorf("missing tag `in` for %s of %s", field.Name(), op.OperationId))
+			b.errs = append(b.errs, errors.Errorf("missing tag `in` for %s of %s", field.Name(), op.OperationId))
+			return true
 		}
 
 		tf, err := transformer.NewTransformer(ctx, field.Type(), transformer.Option{
 			MIME: strings.Split(field.Tag().Get("mime"), ",")[0],
 		})
 		if err != nil {
-			panic(err)
+			b.errs = append(b.errs, errors.Wrapf(err, "%s of %s", field.Name(), op.OperationId))
+			return true
 		}
 
 		v, _ := typesx.TryNew(field.Type())
 
 		schema := b.SchemaFromType(ctx, v.Interface(), false)
 
+		if schema != nil {
+			readOnly, writeOnly := readOnlyWriteOnly(field.Tag())
+			schema.ReadOnly = schema.ReadOnly || readOnly
+			schema.WriteOnly = schema.WriteOnly || writeOnly
+		}
+
+		var docLines []string
+		if docer != nil {
+			if lines, ok := docer.RuntimeDoc(field.Name()); ok {
+				docLines = lines
+			}
+		}
+
+		deprecated := isDeprecatedDoc(docLines)
+		if schema != nil {
+			schema.Deprecated = schema.Deprecated || deprecated
+		}
+
 		switch location {
 		case "body":
 			reqBody := op.RequestBody
@@ -305,35 +388,95 @@ func (b *scanner) scanParameterOrRequestBody(ctx context.Context, op *openapi.Op
 			}
 
 			s := schema
-			if docer != nil {
-				if lines, ok := docer.RuntimeDoc(field.Name()); ok {
-					ds := &jsonschema.Schema{
-						SchemaBasic: jsonschema.SchemaBasic{
-							Description: strings.Join(lines, "\n"),
-						},
-					}
-					if schema == nil {
-						s = ds
-					} else {
-						s = jsonschema.AllOf(schema, ds)
-					}
+			if len(docLines) > 0 {
+				ds := &jsonschema.Schema{
+					SchemaBasic: jsonschema.SchemaBasic{
+						Description: strings.Join(docLines, "\n"),
+						Deprecated:  deprecated,
+					},
 				}
+				if schema == nil {
+					s = ds
+				} else {
+					s = jsonschema.AllOf(schema, ds)
+				}
+			}
+
+			mimeType := strings.Split(field.Tag().Get("mime"), ",")[0]
+			if mimeType == "multipart/form-data" {
+				reqBody.AddContent(mimeType, b.multipartMediaType(ctx, field.Type()))
+			} else {
+				reqBody.AddContent(tf.Names()[0], openapi.NewMediaTypeWithSchema(s))
 			}
-			reqBody.AddContent(tf.Names()[0], openapi.NewMediaTypeWithSchema(s))
 		case "query":
-			op.AddParameter(openapi.QueryParameter(fieldDisplayName, schema, !omitempty))
+			p := openapi.QueryParameter(fieldDisplayName, schema, !omitempty)
+			p.Deprecated = deprecated
+			op.AddParameter(p)
 		case "cookie":
-			op.AddParameter(openapi.CookieParameter(fieldDisplayName, schema, !omitempty))
+			p := openapi.CookieParameter(fieldDisplayName, schema, !omitempty)
+			p.Deprecated = deprecated
+			op.AddParameter(p)
 		case "header":
-			op.AddParameter(openapi.HeaderParameter(fieldDisplayName, schema, !omitempty))
+			p := openapi.HeaderParameter(fieldDisplayName, schema, !omitempty)
+			p.Deprecated = deprecated
+			op.AddParameter(p)
 		case "path":
-			op.AddParameter(openapi.PathParameter(fieldDisplayName, schema))
+			p := openapi.PathParameter(fieldDisplayName, schema)
+			p.Deprecated = deprecated
+			op.AddParameter(p)
 		}
 
 		return true
 	}, "in")
 }
 
+// multipartMediaType builds the multipart/form-data media type for a body
+// field whose type holds the individual form parts: `string`/`binary` for
+// a courierhttp.FileHeader part, the part's own schema otherwise.
+func (b *scanner) multipartMediaType(ctx context.Context, t typesx.Type) *openapi.MediaType {
+	schema := &jsonschema.Schema{
+		SchemaBasic: jsonschema.SchemaBasic{
+			Type: "object",
+		},
+		Properties: map[string]*jsonschema.Schema{},
+	}
+	encoding := map[string]*openapi.Encoding{}
+	fileHeaderType := reflect.TypeOf((*courierhttp.FileHeader)(nil)).Elem()
+
+	typesx.EachField(t, "name", func(field typesx.StructField, fieldDisplayName string, omitempty bool) bool {
+		v, _ := typesx.TryNew(field.Type())
+
+		partContentType := strings.Split(field.Tag().Get("mime"), ",")[0]
+
+		if v.IsValid() && v.Type().Implements(fileHeaderType) {
+			schema.Properties[fieldDisplayName] = &jsonschema.Schema{
+				SchemaBasic: jsonschema.SchemaBasic{
+					Type:   "string",
+					Format: "binary",
+				},
+			}
+			if partContentType == "" {
+				partContentType = "application/octet-stream"
+			}
+		} else {
+			schema.Properties[fieldDisplayName] = b.SchemaFromType(ctx, v.Interface(), false)
+		}
+
+		if !omitempty {
+			schema.Required = append(schema.Required, fieldDisplayName)
+		}
+
+		encoding[fieldDisplayName] = &openapi.Encoding{ContentType: partContentType}
+
+		return true
+	}, "name")
+
+	return &openapi.MediaType{
+		Schema:   schema,
+		Encoding: encoding,
+	}
+}
+
 func tagValueAndFlagsByTagString(tagString string) (string, map[string]bool) {
 	valueAndFlags := strings.Split(tagString, ",")
 	v := valueAndFlags[0]
@@ -345,3 +488,32 @@ func tagValueAndFlagsByTagString(tagString string) (string, map[string]bool) {
 	}
 	return v, tagFlags
 }
+
+// isDeprecatedDoc reports whether a field's runtime doc carries Go's
+// conventional `Deprecated:` marker, so it can be propagated onto the
+// openapi.Parameter / jsonschema.Schema emitted for that field.
+func isDeprecatedDoc(lines []string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "Deprecated:") {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyWriteOnly reports whether a field is marked readOnly / writeOnly,
+// via `validate:"readOnly"` / `validate:"writeOnly"` or the dedicated
+// `openapi:"readOnly"` / `openapi:"writeOnly"` tag.
+func readOnlyWriteOnly(tag reflect.StructTag) (readOnly bool, writeOnly bool) {
+	for _, tagName := range []string{"validate", "openapi"} {
+		for _, flag := range strings.Split(tag.Get(tagName), ",") {
+			switch strings.TrimSpace(flag) {
+			case "readOnly":
+				readOnly = true
+			case "writeOnly":
+				writeOnly = true
+			}
+		}
+	}
+	return
+}
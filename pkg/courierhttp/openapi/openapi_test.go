@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+	"github.com/pkg/errors"
+)
+
+func TestScanErrorsAggregatesEveryProblem(t *testing.T) {
+	errs := ScanErrors{
+		errors.New("missing tag `in` for Name of CreateUser"),
+		errors.New("duplicate schema name User"),
+	}
+
+	msg := errs.Error()
+
+	if !strings.Contains(msg, "2 scan error(s)") {
+		t.Fatalf("expected the aggregate message to report the error count, got %q", msg)
+	}
+	if !strings.Contains(msg, "missing tag `in` for Name of CreateUser") {
+		t.Fatalf("expected the first error to be included, got %q", msg)
+	}
+	if !strings.Contains(msg, "duplicate schema name User") {
+		t.Fatalf("expected the second error to be included, got %q", msg)
+	}
+}
+
+func TestIsDeprecatedDoc(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  []string
+		want bool
+	}{
+		{"no doc", nil, false},
+		{"plain doc", []string{"Name is the user's display name."}, false},
+		{"deprecated marker", []string{"Deprecated: use DisplayName instead."}, true},
+		{"deprecated after description", []string{"Name is the user's display name.", "Deprecated: use DisplayName instead."}, true},
+		{"indented marker", []string{"  Deprecated: legacy field."}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isDeprecatedDoc(c.doc); got != c.want {
+				t.Fatalf("isDeprecatedDoc(%v) = %v, want %v", c.doc, got, c.want)
+			}
+		})
+	}
+}
+
+func TestReadOnlyWriteOnly(t *testing.T) {
+	cases := []struct {
+		name          string
+		tag           reflect.StructTag
+		wantReadOnly  bool
+		wantWriteOnly bool
+	}{
+		{"none", reflect.StructTag(`json:"id"`), false, false},
+		{"validate readOnly", reflect.StructTag(`validate:"readOnly"`), true, false},
+		{"validate writeOnly", reflect.StructTag(`validate:"writeOnly"`), false, true},
+		{"dedicated openapi tag", reflect.StructTag(`openapi:"readOnly"`), true, false},
+		{"combined with other flags", reflect.StructTag(`validate:"required,readOnly"`), true, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			readOnly, writeOnly := readOnlyWriteOnly(c.tag)
+			if readOnly != c.wantReadOnly || writeOnly != c.wantWriteOnly {
+				t.Fatalf("readOnlyWriteOnly(%q) = (%v, %v), want (%v, %v)", c.tag, readOnly, writeOnly, c.wantReadOnly, c.wantWriteOnly)
+			}
+		})
+	}
+}
+
+func TestRegisterSchemaInvokesSchemaPostProcessor(t *testing.T) {
+	var gotName string
+	var gotSchema *jsonschema.Schema
+
+	b := &scanner{
+		o: openapi.NewOpenAPI(),
+		opt: buildOption{
+			schemaPostProcessor: func(name string, s *jsonschema.Schema) {
+				gotName = name
+				gotSchema = s
+			},
+		},
+	}
+
+	schema := &jsonschema.Schema{SchemaBasic: jsonschema.SchemaBasic{Type: "object"}}
+	b.RegisterSchema("#/components/schemas/User", schema)
+
+	if gotName != "User" || gotSchema != schema {
+		t.Fatalf("expected the post-processor to run with (%q, %p), got (%q, %p)", "User", schema, gotName, gotSchema)
+	}
+
+	b.RegisterSchema("#/components/schemas/User", &jsonschema.Schema{})
+	if len(b.errs) != 1 {
+		t.Fatalf("expected re-registering User to record one duplicate-schema error, got %d", len(b.errs))
+	}
+}
+
+// variantsOperator implements CanResponseVariants, the same way an
+// operator returning courierhttp.Response200JSON/Response404JSON-style
+// variants from its handler would.
+type variantsOperator struct{}
+
+func (variantsOperator) Method() string { return http.MethodGet }
+
+func (variantsOperator) ResponseVariants() []courierhttp.ResponseVariant {
+	return []courierhttp.ResponseVariant{
+		courierhttp.Response200JSON[string](""),
+		courierhttp.Response404JSON[string](""),
+	}
+}
+
+func TestScanResponseRegistersEveryResponseVariant(t *testing.T) {
+	b := &scanner{o: openapi.NewOpenAPI()}
+	op := openapi.NewOperation("getThing")
+
+	b.scanResponse(context.Background(), op, &courier.OperatorFactory{Operator: variantsOperator{}})
+
+	if len(op.Responses) != 2 {
+		t.Fatalf("expected scanResponse to register both declared variants, got %d responses: %+v", len(op.Responses), op.Responses)
+	}
+}
+
+// testDocUser declares a field in every location the scanner understands,
+// plus a nested body struct, mirroring how a real request struct declares
+// its fields. Its RuntimeDoc marks only the header field as deprecated.
+type testDocUser struct {
+	ID      string         `in:"path" name:"id"`
+	Sort    string         `in:"query" name:"sort"`
+	Token   string         `in:"header" name:"x-token"`
+	Session string         `in:"cookie" name:"session"`
+	Profile testDocProfile `in:"body"`
+}
+
+type testDocProfile struct {
+	// Nickname is the legacy display name.
+	//
+	// Deprecated: use Name instead.
+	Nickname string `name:"nickname"`
+}
+
+func (testDocUser) RuntimeDoc(names ...string) ([]string, bool) {
+	if len(names) == 0 {
+		return nil, false
+	}
+	switch names[0] {
+	case "Token":
+		return []string{"Deprecated: send Authorization instead."}, true
+	default:
+		return nil, false
+	}
+}
+
+func (testDocProfile) RuntimeDoc(names ...string) ([]string, bool) {
+	if len(names) == 0 {
+		return nil, false
+	}
+	if names[0] == "Nickname" {
+		return []string{"Nickname is the legacy display name.", "", "Deprecated: use Name instead."}, true
+	}
+	return nil, false
+}
+
+func TestCanRuntimeDocDeprecationAcrossLocations(t *testing.T) {
+	var u testDocUser
+	docer := CanRuntimeDoc(u)
+
+	lines, ok := docer.RuntimeDoc("Token")
+	if !ok || !isDeprecatedDoc(lines) {
+		t.Fatalf("expected the header field's doc to be detected as deprecated, got %v ok=%v", lines, ok)
+	}
+
+	if _, ok := docer.RuntimeDoc("Sort"); ok {
+		t.Fatalf("expected the query field to have no doc")
+	}
+
+	var p testDocProfile
+	nestedDocer := CanRuntimeDoc(p)
+	nestedLines, ok := nestedDocer.RuntimeDoc("Nickname")
+	if !ok || !isDeprecatedDoc(nestedLines) {
+		t.Fatalf("expected the nested struct field's doc to be detected as deprecated, got %v ok=%v", nestedLines, ok)
+	}
+}
@@ -2,12 +2,18 @@ package courierhttp
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/octohelm/courier/pkg/statuserror"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/textproto"
 	"net/url"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/octohelm/courier/pkg/courier"
 	transformer "github.com/octohelm/courier/pkg/transformer/core"
@@ -42,6 +48,133 @@ type FileHeader interface {
 	Header() http.Header
 }
 
+// multipartFilePart adapts a *multipart.Part as a FileHeader.
+type multipartFilePart struct {
+	*multipart.Part
+}
+
+func (p *multipartFilePart) Filename() string {
+	return p.FileName()
+}
+
+func (p *multipartFilePart) Header() http.Header {
+	return http.Header(p.Part.Header)
+}
+
+var (
+	fileHeaderType     = reflect.TypeOf((*FileHeader)(nil)).Elem()
+	multipartReaderPtr = reflect.TypeOf((*multipart.Reader)(nil))
+)
+
+// BindMultipart populates dst, a pointer to a struct whose fields are
+// tagged `name:"..."`, from the parts of a multipart/form-data body. A
+// FileHeader field receives its part unbuffered and must be the last
+// named field still owed a part: binding stops there, and an error is
+// returned if any other named field hasn't received its part yet. A
+// *multipart.Reader field takes over the reader itself. A readOnly part
+// (see isReadOnlyField) is dropped rather than bound.
+func BindMultipart(reader *multipart.Reader, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("courierhttp: BindMultipart dst must be a non-nil pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	fieldIndexByName := map[string]int{}
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).Type == multipartReaderPtr {
+			// a field wanting the raw reader drives part handling itself from
+			// here on, so it must be set before any part is pulled off reader.
+			rv.Field(i).Set(reflect.ValueOf(reader))
+			return nil
+		}
+		if name := strings.Split(rt.Field(i).Tag.Get("name"), ",")[0]; name != "" {
+			fieldIndexByName[name] = i
+		}
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		i, ok := fieldIndexByName[part.FormName()]
+		if !ok {
+			_ = part.Close()
+			continue
+		}
+		delete(fieldIndexByName, part.FormName())
+
+		if isReadOnlyField(rt.Field(i).Tag) {
+			// readOnly fields are server-generated and never accepted from
+			// an inbound request; silently drop the part instead of binding it.
+			_ = part.Close()
+			continue
+		}
+
+		field := rv.Field(i)
+
+		if field.Type() == fileHeaderType {
+			// reader.NextPart (and part.Close) drains whatever is left of the
+			// current part, so fetching the next part here would silently
+			// empty this file out from under the caller. Hand the still-open
+			// part straight to dst and stop.
+			field.Set(reflect.ValueOf(&multipartFilePart{Part: part}))
+
+			if len(fieldIndexByName) > 0 {
+				pending := make([]string, 0, len(fieldIndexByName))
+				for name := range fieldIndexByName {
+					pending = append(pending, name)
+				}
+				sort.Strings(pending)
+				return fmt.Errorf("courierhttp: BindMultipart: field %q must be the last named field in the form, but %s has not received a part yet", part.FormName(), strings.Join(pending, ", "))
+			}
+
+			return nil
+		}
+
+		value, err := io.ReadAll(part)
+		_ = part.Close()
+		if err != nil {
+			return err
+		}
+		if err := setScalarField(field, string(value)); err != nil {
+			return err
+		}
+	}
+}
+
+func setScalarField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	}
+	return nil
+}
+
 type Request interface {
 	Context() context.Context
 
@@ -56,6 +189,170 @@ type Request interface {
 	Underlying() *http.Request
 }
 
+// FieldViolation describes one parameter or request body field that failed
+// binding or validation.
+type FieldViolation struct {
+	Location string `json:"location"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"`
+}
+
+// ValidationErrors aggregates every FieldViolation found while binding a
+// single request.
+type ValidationErrors struct {
+	Errors []FieldViolation `json:"errors"`
+}
+
+// AddViolation records one field failure.
+func (e *ValidationErrors) AddViolation(location string, name string, reason string) {
+	e.Errors = append(e.Errors, FieldViolation{Location: location, Name: name, Reason: reason})
+}
+
+// HasErrors reports whether any violation has been recorded.
+func (e *ValidationErrors) HasErrors() bool {
+	return len(e.Errors) > 0
+}
+
+func (e *ValidationErrors) Error() string {
+	if len(e.Errors) == 0 {
+		return "validation failed"
+	}
+	reasons := make([]string, len(e.Errors))
+	for i, v := range e.Errors {
+		reasons[i] = fmt.Sprintf("%s %s: %s", v.Location, v.Name, v.Reason)
+	}
+	return "validation failed: " + strings.Join(reasons, "; ")
+}
+
+// StatusCode reports HTTP 422.
+func (e *ValidationErrors) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// CanValidate is implemented by a bound field value that validates itself.
+type CanValidate interface {
+	Validate() error
+}
+
+// ValidateRequest runs CanValidate on every `in`-tagged field of req,
+// collecting every failure into a single ValidationErrors instead of
+// returning on the first one. It returns nil once every field validates.
+func ValidateRequest(req any) error {
+	rv := reflect.ValueOf(req)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rt := rv.Type()
+	violations := &ValidationErrors{}
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		location := strings.Split(field.Tag.Get("in"), ",")[0]
+		if location == "" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if !fv.CanInterface() {
+			continue
+		}
+
+		validatable, ok := fv.Interface().(CanValidate)
+		if !ok && fv.CanAddr() {
+			validatable, ok = fv.Addr().Interface().(CanValidate)
+		}
+		if !ok {
+			continue
+		}
+
+		if err := validatable.Validate(); err != nil {
+			name := strings.Split(field.Tag.Get("name"), ",")[0]
+			if name == "" {
+				name = field.Name
+			}
+			violations.AddViolation(location, name, err.Error())
+		}
+	}
+
+	if !violations.HasErrors() {
+		return nil
+	}
+	return violations
+}
+
+// BindRequest binds req's query, header, cookie and path parameters, and
+// its body (the field tagged `in:"body"`, if any), into dst, then runs
+// ValidateRequest over it. Every binding and validation failure is
+// returned together as one ValidationErrors. A readOnly field (see
+// isReadOnlyField) is never bound.
+func BindRequest(ctx context.Context, req Request, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("courierhttp: BindRequest dst must be a non-nil pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	violations := &ValidationErrors{}
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		if isReadOnlyField(field.Tag) {
+			continue
+		}
+
+		location := strings.Split(field.Tag.Get("in"), ",")[0]
+		name := strings.Split(field.Tag.Get("name"), ",")[0]
+
+		switch location {
+		case "query", "header", "cookie", "path":
+			values := req.Values(location, name)
+			if len(values) == 0 {
+				continue
+			}
+			if err := setScalarField(rv.Field(i), values[0]); err != nil {
+				violations.AddViolation(location, name, err.Error())
+			}
+		case "body":
+			body := req.Body()
+			if body == nil {
+				continue
+			}
+			defer body.Close()
+
+			tf, err := transformer.NewTransformer(ctx, typesutil.FromRType(field.Type), transformer.Option{
+				MIME: req.Header().Get("Content-Type"),
+			})
+			if err != nil {
+				return err
+			}
+			if err := tf.DecodeFrom(ctx, body, rv.Field(i).Addr().Interface()); err != nil {
+				violations.AddViolation(location, name, err.Error())
+			}
+		}
+	}
+
+	if err := ValidateRequest(dst); err != nil {
+		verr, ok := err.(*ValidationErrors)
+		if !ok {
+			return err
+		}
+		violations.Errors = append(violations.Errors, verr.Errors...)
+	}
+	if violations.HasErrors() {
+		return violations
+	}
+	return nil
+}
+
 type ResponseSetting interface {
 	SetStatusCode(statusCode int)
 	SetLocation(location *url.URL)
@@ -110,6 +407,76 @@ type Response[T any] interface {
 	courier.MetadataCarrier
 }
 
+// ResponseVariant is one possible shape of a multi-status response: its
+// own status code, content type, and the value used to derive its schema.
+// A handler that may answer 200 on success and 404 with a typed error
+// body, say, returns a value whose type implements ResponseVariant.
+type ResponseVariant interface {
+	StatusCodeDescriber
+	ContentTypeDescriber
+
+	// ContentSchema returns the zero value used to derive the schema of
+	// this variant's body. It is not meant to be encoded directly.
+	ContentSchema() any
+}
+
+type responseVariant[T any] struct {
+	body        T
+	statusCode  int
+	contentType string
+}
+
+func (v *responseVariant[T]) StatusCode() int {
+	return v.statusCode
+}
+
+func (v *responseVariant[T]) ContentType() string {
+	return v.contentType
+}
+
+func (v *responseVariant[T]) ContentSchema() any {
+	var zero T
+	return zero
+}
+
+func (v *responseVariant[T]) Underlying() T {
+	return v.body
+}
+
+func (v *responseVariant[T]) underlyingValue() any {
+	return v.body
+}
+
+// canUnderlyingValue lets WriteResponse reach a responseVariant's body
+// regardless of its T.
+type canUnderlyingValue interface {
+	underlyingValue() any
+}
+
+// NewResponseVariant creates a ResponseVariant carrying body as its content.
+func NewResponseVariant[T any](statusCode int, contentType string, body T) ResponseVariant {
+	return &responseVariant[T]{
+		body:        body,
+		statusCode:  statusCode,
+		contentType: contentType,
+	}
+}
+
+// Response200JSON declares a 200 application/json response variant.
+func Response200JSON[T any](body T) ResponseVariant {
+	return NewResponseVariant(http.StatusOK, "application/json", body)
+}
+
+// Response201JSON declares a 201 application/json response variant.
+func Response201JSON[T any](body T) ResponseVariant {
+	return NewResponseVariant(http.StatusCreated, "application/json", body)
+}
+
+// Response404JSON declares a 404 application/json response variant.
+func Response404JSON[T any](body T) ResponseVariant {
+	return NewResponseVariant(http.StatusNotFound, "application/json", body)
+}
+
 type ResponseWriter interface {
 	WriteResponse(ctx context.Context, rw http.ResponseWriter, req Request) error
 }
@@ -181,6 +548,17 @@ func (r *response[T]) WriteResponse(ctx context.Context, rw http.ResponseWriter,
 		resp = statuserror.FromErr(err)
 	}
 
+	// a ResponseVariant carries its own status code and content type,
+	// regardless of what other variants the same operation could return.
+	if variant, ok := resp.(ResponseVariant); ok {
+		r.SetStatusCode(variant.StatusCode())
+		r.SetContentType(variant.ContentType())
+
+		if u, ok := variant.(canUnderlyingValue); ok {
+			resp = u.underlyingValue()
+		}
+	}
+
 	if statusCodeDescriber, ok := resp.(StatusCodeDescriber); ok {
 		r.SetStatusCode(statusCodeDescriber.StatusCode())
 	}
@@ -251,15 +629,78 @@ func (r *response[T]) WriteResponse(ctx context.Context, rw http.ResponseWriter,
 			return err
 		}
 	default:
-		tf, err := transformer.NewTransformer(ctx, typesutil.FromRType(reflect.TypeOf(resp)), transformer.Option{
+		body := dropWriteOnlyFields(resp)
+
+		tf, err := transformer.NewTransformer(ctx, typesutil.FromRType(reflect.TypeOf(body)), transformer.Option{
 			MIME: r.contentType,
 		})
 		if err != nil {
 			return err
 		}
-		if err := tf.EncodeTo(transformer.ContextWithStatusCode(ctx, r.statusCode), rw, resp); err != nil {
+		if err := tf.EncodeTo(transformer.ContextWithStatusCode(ctx, r.statusCode), rw, body); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// dropWriteOnlyFields returns a pointer to a shallow copy of v's struct
+// with every field tagged `validate:"writeOnly"` or `openapi:"writeOnly"`
+// zeroed out. v is
+// returned unchanged if it isn't a struct (or pointer to one), or has no
+// such field.
+func dropWriteOnlyFields(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+
+	rt := rv.Type()
+
+	hasWriteOnly := false
+	for i := 0; i < rt.NumField(); i++ {
+		if isWriteOnlyField(rt.Field(i).Tag) {
+			hasWriteOnly = true
+			break
+		}
+	}
+	if !hasWriteOnly {
+		return v
+	}
+
+	out := reflect.New(rt).Elem()
+	out.Set(rv)
+	for i := 0; i < rt.NumField(); i++ {
+		if isWriteOnlyField(rt.Field(i).Tag) {
+			out.Field(i).Set(reflect.Zero(rt.Field(i).Type))
+		}
+	}
+	return out.Addr().Interface()
+}
+
+func isWriteOnlyField(tag reflect.StructTag) bool {
+	return hasFieldFlag(tag, "writeOnly")
+}
+
+// isReadOnlyField reports whether a field is marked readOnly via
+// `validate:"readOnly"` or the dedicated `openapi:"readOnly"` tag.
+func isReadOnlyField(tag reflect.StructTag) bool {
+	return hasFieldFlag(tag, "readOnly")
+}
+
+func hasFieldFlag(tag reflect.StructTag, flagName string) bool {
+	for _, tagName := range []string{"validate", "openapi"} {
+		for _, flag := range strings.Split(tag.Get(tagName), ",") {
+			if strings.TrimSpace(flag) == flagName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package courierhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeRequest struct{}
+
+func (fakeRequest) Context() context.Context               { return context.Background() }
+func (fakeRequest) ServiceName() string                    { return "" }
+func (fakeRequest) Method() string                         { return http.MethodGet }
+func (fakeRequest) Path() string                           { return "/" }
+func (fakeRequest) Header() http.Header                    { return http.Header{} }
+func (fakeRequest) Values(in string, name string) []string { return nil }
+func (fakeRequest) Body() io.ReadCloser                    { return nil }
+func (fakeRequest) Underlying() *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/", nil)
+}
+
+var _ Request = fakeRequest{}
+
+// queryRequest is a fakeRequest whose Values answers from a fixed set of
+// query parameters, for exercising BindRequest's non-body binding.
+type queryRequest struct {
+	fakeRequest
+	query map[string][]string
+}
+
+func (r queryRequest) Values(in string, name string) []string {
+	if in != "query" {
+		return nil
+	}
+	return r.query[name]
+}
+
+type requiredString string
+
+func (v requiredString) Validate() error {
+	if v == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+func TestValidateRequestAggregatesEveryViolation(t *testing.T) {
+	type CreateUser struct {
+		Name  requiredString `in:"query" name:"name"`
+		Email requiredString `in:"body" name:"email"`
+	}
+
+	err := ValidateRequest(&CreateUser{})
+	if err == nil {
+		t.Fatal("expected both fields to fail validation")
+	}
+
+	verr, ok := err.(*ValidationErrors)
+	if !ok {
+		t.Fatalf("expected a *ValidationErrors, got %T", err)
+	}
+
+	if len(verr.Errors) != 2 {
+		t.Fatalf("expected both violations to be collected in one pass, got %d: %+v", len(verr.Errors), verr.Errors)
+	}
+	if verr.StatusCode() != 422 {
+		t.Fatalf("expected status code 422, got %d", verr.StatusCode())
+	}
+}
+
+func TestValidateRequestReturnsNilWhenEveryFieldValidates(t *testing.T) {
+	type CreateUser struct {
+		Name requiredString `in:"query" name:"name"`
+	}
+
+	if err := ValidateRequest(&CreateUser{Name: "jojo"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestBindRequestValidatesBoundFields(t *testing.T) {
+	type Search struct {
+		Name requiredString `in:"query" name:"name"`
+	}
+
+	req := queryRequest{query: map[string][]string{"name": {""}}}
+
+	dst := &Search{}
+	err := BindRequest(context.Background(), req, dst)
+	if err == nil {
+		t.Fatal("expected the bound empty Name to fail CanValidate")
+	}
+	if _, ok := err.(*ValidationErrors); !ok {
+		t.Fatalf("expected a *ValidationErrors, got %T", err)
+	}
+}
+
+func TestBindRequestBindsQueryParameters(t *testing.T) {
+	type Search struct {
+		Name requiredString `in:"query" name:"name"`
+	}
+
+	req := queryRequest{query: map[string][]string{"name": {"jojo"}}}
+
+	dst := &Search{}
+	if err := BindRequest(context.Background(), req, dst); err != nil {
+		t.Fatalf("BindRequest failed: %v", err)
+	}
+	if dst.Name != "jojo" {
+		t.Fatalf("expected Name to be bound from the query, got %q", dst.Name)
+	}
+}
+
+func TestBindRequestDropsReadOnlyField(t *testing.T) {
+	type CreateUser struct {
+		ID   requiredString `in:"query" name:"id" validate:"readOnly"`
+		Name requiredString `in:"query" name:"name"`
+	}
+
+	req := queryRequest{query: map[string][]string{
+		"id":   {"server-assigned-should-be-ignored"},
+		"name": {"jojo"},
+	}}
+
+	dst := &CreateUser{}
+	if err := BindRequest(context.Background(), req, dst); err != nil {
+		t.Fatalf("BindRequest failed: %v", err)
+	}
+	if dst.ID != "" {
+		t.Fatalf("expected readOnly field ID to be dropped, got %q", dst.ID)
+	}
+	if dst.Name != "jojo" {
+		t.Fatalf("expected Name to still be bound, got %q", dst.Name)
+	}
+}
+
+func TestDropWriteOnlyFields(t *testing.T) {
+	type User struct {
+		Name     string `json:"name"`
+		Password string `json:"password" validate:"writeOnly"`
+	}
+
+	out := dropWriteOnlyFields(User{Name: "jojo", Password: "hunter2"})
+
+	u, ok := out.(*User)
+	if !ok {
+		t.Fatalf("expected a *User, got %T", out)
+	}
+	if u.Name != "jojo" {
+		t.Fatalf("expected Name to be preserved, got %q", u.Name)
+	}
+	if u.Password != "" {
+		t.Fatalf("expected writeOnly Password to be zeroed, got %q", u.Password)
+	}
+}
+
+func TestDropWriteOnlyFieldsLeavesPlainStructsUntouched(t *testing.T) {
+	type Ping struct {
+		OK bool `json:"ok"`
+	}
+
+	out := dropWriteOnlyFields(Ping{OK: true})
+
+	if out.(Ping).OK != true {
+		t.Fatalf("expected a struct with no writeOnly fields to be returned unchanged, got %+v", out)
+	}
+}
+
+func TestWriteResponseHonorsResponseVariant(t *testing.T) {
+	variant := Response201JSON[io.Reader](strings.NewReader("created"))
+
+	resp := Wrap[any](variant)
+
+	rw, ok := resp.(ResponseWriter)
+	if !ok {
+		t.Fatalf("expected Response to implement ResponseWriter, got %T", resp)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := rw.WriteResponse(context.Background(), rec, fakeRequest{}); err != nil {
+		t.Fatalf("WriteResponse failed: %v", err)
+	}
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected the variant's own status code %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected the variant's own content type, got %q", ct)
+	}
+	if rec.Body.String() != "created" {
+		t.Fatalf("expected the variant's underlying body to be written, got %q", rec.Body.String())
+	}
+}
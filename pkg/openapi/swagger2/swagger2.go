@@ -0,0 +1,253 @@
+// Package swagger2 converts a built *openapi.OpenAPI (OpenAPI 3) document
+// into a Swagger 2.0 document, for tooling that has not moved past the
+// legacy spec. It is a one-way, best-effort conversion: constructs that
+// have no Swagger 2.0 equivalent (oneOf, anyOf) are demoted to an
+// `x-` vendor extension rather than dropped silently.
+package swagger2
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+)
+
+// Document is a Swagger 2.0 (OpenAPI 2) document.
+type Document struct {
+	Swagger     string                        `json:"swagger"`
+	Info        *openapi.Info                 `json:"info,omitempty"`
+	Host        string                        `json:"host,omitempty"`
+	BasePath    string                        `json:"basePath,omitempty"`
+	Schemes     []string                      `json:"schemes,omitempty"`
+	Consumes    []string                      `json:"consumes,omitempty"`
+	Produces    []string                      `json:"produces,omitempty"`
+	Paths       map[string]*PathItem          `json:"paths"`
+	Definitions map[string]*jsonschema.Schema `json:"definitions,omitempty"`
+}
+
+// PathItem groups every operation declared for a single path, one per HTTP
+// method.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+}
+
+// Operation is a Swagger 2.0 operation: requestBody.content is split into
+// formData/body parameters, and content maps are flattened to
+// consumes/produces.
+type Operation struct {
+	OperationId string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Consumes    []string             `json:"consumes,omitempty"`
+	Produces    []string             `json:"produces,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a Swagger 2.0 parameter. Unlike OpenAPI 3, a body/formData
+// parameter carries its schema directly rather than through content.
+type Parameter struct {
+	In         string             `json:"in"`
+	Name       string             `json:"name"`
+	Required   bool               `json:"required,omitempty"`
+	Deprecated bool               `json:"x-deprecated,omitempty"`
+	Schema     *jsonschema.Schema `json:"schema,omitempty"`
+}
+
+// Response is a Swagger 2.0 response: its body schema sits directly under
+// `schema`, not under a content map keyed by media type.
+type Response struct {
+	Description string             `json:"description"`
+	Schema      *jsonschema.Schema `json:"schema,omitempty"`
+}
+
+// Convert turns an OpenAPI 3 document into a Swagger 2.0 Document.
+//
+// `$ref`s pointing at `#/components/schemas/...` are rewritten to
+// `#/definitions/...` as rewriteRefs describes; requestBody content is
+// split into `formData` parameters (multipart/form-data, urlencoded) or a
+// single `body` parameter (everything else); and `content` maps on
+// operations are flattened onto `consumes`/`produces`.
+func Convert(doc *openapi.OpenAPI) *Document {
+	d := &Document{
+		Swagger:     "2.0",
+		Info:        doc.Info,
+		Paths:       map[string]*PathItem{},
+		Definitions: map[string]*jsonschema.Schema{},
+	}
+
+	for name, schema := range doc.Components.Schemas {
+		d.Definitions[name] = rewriteRefs(schema)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		item := doc.Paths[p]
+		d.Paths[p] = &PathItem{
+			Get:    convertOperation(item.Get),
+			Put:    convertOperation(item.Put),
+			Post:   convertOperation(item.Post),
+			Delete: convertOperation(item.Delete),
+			Patch:  convertOperation(item.Patch),
+		}
+	}
+
+	return d
+}
+
+func convertOperation(op *openapi.Operation) *Operation {
+	if op == nil {
+		return nil
+	}
+
+	o := &Operation{
+		OperationId: op.OperationId,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+		Tags:        op.Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, p := range op.Parameters {
+		o.Parameters = append(o.Parameters, &Parameter{
+			In:         p.In,
+			Name:       p.Name,
+			Required:   p.Required,
+			Deprecated: p.Deprecated,
+			Schema:     rewriteRefs(p.Schema),
+		})
+	}
+
+	if op.RequestBody != nil {
+		hasBodyParameter := false
+
+		for mime, mt := range op.RequestBody.Content {
+			schema := rewriteRefs(mt.Schema)
+
+			if isFormMime(mime) {
+				o.Consumes = append(o.Consumes, mime)
+				o.Parameters = append(o.Parameters, formDataParameters(schema)...)
+				continue
+			}
+
+			o.Consumes = append(o.Consumes, mime)
+			if hasBodyParameter {
+				continue
+			}
+			hasBodyParameter = true
+			o.Parameters = append(o.Parameters, &Parameter{
+				In:       "body",
+				Name:     "body",
+				Required: op.RequestBody.Required,
+				Schema:   schema,
+			})
+		}
+	}
+
+	for statusCode, resp := range op.Responses {
+		var schema *jsonschema.Schema
+
+		for mime, mt := range resp.Content {
+			o.Produces = append(o.Produces, mime)
+			if schema == nil {
+				schema = rewriteRefs(mt.Schema)
+			}
+		}
+
+		o.Responses[statusCode] = &Response{
+			Schema: schema,
+		}
+	}
+
+	return o
+}
+
+func isFormMime(mime string) bool {
+	return mime == "multipart/form-data" || mime == "application/x-www-form-urlencoded"
+}
+
+func formDataParameters(schema *jsonschema.Schema) []*Parameter {
+	if schema == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]*Parameter, 0, len(names))
+	for _, name := range names {
+		params = append(params, &Parameter{
+			In:     "formData",
+			Name:   name,
+			Schema: schema.Properties[name],
+		})
+	}
+	return params
+}
+
+// rewriteRefs returns a copy of s whose `$ref`, and those of its
+// Properties (recursively), pointing at `#/components/schemas/...` are
+// rewritten to `#/definitions/...`, and with `oneOf`/`anyOf` -- which
+// Swagger 2.0 has no equivalent for -- demoted to an `x-oneOf`/`x-anyOf`
+// vendor extension. `$ref`s under any other subschema (items, allOf, and
+// the demoted oneOf/anyOf members) are left as they are. The source
+// schema's `$ref`s and Properties, typically shared with an already-built
+// OpenAPI 3 document, are not modified.
+func rewriteRefs(s *jsonschema.Schema) *jsonschema.Schema {
+	if s == nil {
+		return nil
+	}
+
+	c := cloneSchema(s)
+
+	if c.Ref != "" {
+		c.Ref = strings.Replace(c.Ref, "#/components/schemas/", "#/definitions/", 1)
+	}
+
+	if len(c.OneOf) > 0 {
+		c.AddExtension("x-oneOf", c.OneOf)
+		c.OneOf = nil
+	}
+
+	if len(c.AnyOf) > 0 {
+		c.AddExtension("x-anyOf", c.AnyOf)
+		c.AnyOf = nil
+	}
+
+	for name, prop := range c.Properties {
+		c.Properties[name] = rewriteRefs(prop)
+	}
+
+	return c
+}
+
+// cloneSchema makes a shallow copy of s, along with its own Properties map
+// so rewriteRefs can replace entries in the copy without touching s.
+func cloneSchema(s *jsonschema.Schema) *jsonschema.Schema {
+	c := *s
+
+	if s.Properties != nil {
+		c.Properties = make(map[string]*jsonschema.Schema, len(s.Properties))
+		for name, prop := range s.Properties {
+			c.Properties[name] = prop
+		}
+	}
+
+	return &c
+}
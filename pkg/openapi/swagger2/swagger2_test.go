@@ -0,0 +1,178 @@
+package swagger2
+
+import (
+	"testing"
+
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+)
+
+func TestRewriteRefsDoesNotMutateSource(t *testing.T) {
+	prop := &jsonschema.Schema{
+		SchemaBasic: jsonschema.SchemaBasic{
+			Ref: "#/components/schemas/Address",
+		},
+	}
+
+	original := &jsonschema.Schema{
+		Properties: map[string]*jsonschema.Schema{
+			"address": prop,
+		},
+	}
+
+	converted := rewriteRefs(original)
+
+	if converted.Properties["address"].Ref != "#/definitions/Address" {
+		t.Fatalf("expected converted ref to be rewritten, got %q", converted.Properties["address"].Ref)
+	}
+
+	if prop.Ref != "#/components/schemas/Address" {
+		t.Fatalf("expected source schema ref to be left untouched, got %q", prop.Ref)
+	}
+
+	if original.Properties["address"] != prop {
+		t.Fatal("expected the source Properties map to still point at the original schema")
+	}
+}
+
+func TestConvertFlattensPathsParametersAndContent(t *testing.T) {
+	userSchema := &jsonschema.Schema{
+		SchemaBasic: jsonschema.SchemaBasic{Ref: "#/components/schemas/User"},
+	}
+
+	doc := &openapi.OpenAPI{
+		Info: &openapi.Info{Title: "test", Version: "1.0.0"},
+		Components: openapi.Components{
+			Schemas: map[string]*jsonschema.Schema{
+				"User": {SchemaBasic: jsonschema.SchemaBasic{Type: "object"}},
+			},
+		},
+		Paths: map[string]*openapi.PathItem{
+			"/users": {
+				Post: &openapi.Operation{
+					OperationId: "CreateUser",
+					Parameters: []*openapi.Parameter{
+						{In: "query", Name: "sort", Required: true, Schema: &jsonschema.Schema{SchemaBasic: jsonschema.SchemaBasic{Type: "string"}}},
+					},
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content: map[string]*openapi.MediaType{
+							"application/json": {Schema: userSchema},
+						},
+					},
+					Responses: map[string]*openapi.Response{
+						"200": {
+							Content: map[string]*openapi.MediaType{
+								"application/json": {Schema: userSchema},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	d := Convert(doc)
+
+	if _, ok := d.Definitions["User"]; !ok {
+		t.Fatal("expected the User schema to be carried into Definitions")
+	}
+
+	item, ok := d.Paths["/users"]
+	if !ok || item.Post == nil {
+		t.Fatalf("expected /users to convert a POST operation, got %+v", item)
+	}
+
+	op := item.Post
+	if op.OperationId != "CreateUser" {
+		t.Fatalf("expected OperationId to be carried over, got %q", op.OperationId)
+	}
+
+	var queryParam, bodyParam *Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "query":
+			queryParam = p
+		case "body":
+			bodyParam = p
+		}
+	}
+	if queryParam == nil || !queryParam.Required {
+		t.Fatalf("expected the required query parameter to convert, got %+v", queryParam)
+	}
+	if bodyParam == nil {
+		t.Fatal("expected the JSON request body to become a single body parameter")
+	}
+	if bodyParam.Schema.Ref != "#/definitions/User" {
+		t.Fatalf("expected the body parameter's $ref to be rewritten, got %q", bodyParam.Schema.Ref)
+	}
+
+	if len(op.Consumes) != 1 || op.Consumes[0] != "application/json" {
+		t.Fatalf("expected Consumes to carry the request body mime type, got %v", op.Consumes)
+	}
+	if len(op.Produces) != 1 || op.Produces[0] != "application/json" {
+		t.Fatalf("expected Produces to carry the response mime type, got %v", op.Produces)
+	}
+
+	resp, ok := op.Responses["200"]
+	if !ok || resp.Schema == nil || resp.Schema.Ref != "#/definitions/User" {
+		t.Fatalf("expected the 200 response's $ref to be rewritten, got %+v", resp)
+	}
+}
+
+func TestConvertSplitsMultipartIntoFormDataParameters(t *testing.T) {
+	doc := &openapi.OpenAPI{
+		Paths: map[string]*openapi.PathItem{
+			"/avatars": {
+				Post: &openapi.Operation{
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content: map[string]*openapi.MediaType{
+							"multipart/form-data": {
+								Schema: &jsonschema.Schema{
+									SchemaBasic: jsonschema.SchemaBasic{Type: "object"},
+									Properties: map[string]*jsonschema.Schema{
+										"avatar": {SchemaBasic: jsonschema.SchemaBasic{Type: "string", Format: "binary"}},
+									},
+								},
+							},
+						},
+					},
+					Responses: map[string]*openapi.Response{},
+				},
+			},
+		},
+	}
+
+	op := Convert(doc).Paths["/avatars"].Post
+
+	if len(op.Parameters) != 1 || op.Parameters[0].In != "formData" || op.Parameters[0].Name != "avatar" {
+		t.Fatalf("expected a single formData parameter named avatar, got %+v", op.Parameters)
+	}
+	if len(op.Consumes) != 1 || op.Consumes[0] != "multipart/form-data" {
+		t.Fatalf("expected Consumes to carry multipart/form-data, got %v", op.Consumes)
+	}
+}
+
+func TestConvertDemotesOneOfAndAnyOf(t *testing.T) {
+	schema := &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{{SchemaBasic: jsonschema.SchemaBasic{Type: "string"}}},
+		AnyOf: []*jsonschema.Schema{{SchemaBasic: jsonschema.SchemaBasic{Type: "integer"}}},
+	}
+
+	doc := &openapi.OpenAPI{
+		Components: openapi.Components{
+			Schemas: map[string]*jsonschema.Schema{"Mixed": schema},
+		},
+		Paths: map[string]*openapi.PathItem{},
+	}
+
+	converted := Convert(doc).Definitions["Mixed"]
+
+	if len(converted.OneOf) != 0 || len(converted.AnyOf) != 0 {
+		t.Fatalf("expected oneOf/anyOf to be demoted off the converted schema, got %+v", converted)
+	}
+	if len(schema.OneOf) == 0 || len(schema.AnyOf) == 0 {
+		t.Fatal("expected the source schema's oneOf/anyOf to be left untouched")
+	}
+}